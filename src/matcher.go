@@ -0,0 +1,161 @@
+package fzf
+
+import "sync"
+
+// MatcherOptions configures a Matcher returned by NewMatcher. The fields
+// mirror BuildPattern's parameters, since each Query ultimately builds and
+// runs a Pattern with these settings.
+type MatcherOptions struct {
+	Fuzzy     bool
+	Extended  bool
+	CaseMode  Case
+	Forward   bool
+	Nth       []Range
+	Delimiter Delimiter
+}
+
+// Result is a single match produced by Matcher.Query.
+type Result struct {
+	Text string
+	// Offsets are rune-indexed [start, end) pairs into Text, one per
+	// matched term, in the same order fzf highlights them.
+	Offsets [][2]int32
+}
+
+// Matcher lets Go programs run fzf's fuzzy/extended/migemo matching over an
+// in-memory set of lines without spawning the fzf binary or driving a TTY.
+// It owns its own item store and ChunkCache, independent of the
+// package-level ones the CLI uses, so embedding fzf in a long-running
+// program doesn't contend with or leak into the CLI's state.
+//
+// A Matcher is safe for concurrent use: all of its state (item store,
+// ChunkCache, and pattern cache) is guarded by an internal mutex.
+//
+// Matcher deliberately does not go through BuildPattern's package-level
+// pattern cache: that cache is keyed on the query text alone, which is only
+// safe because the CLI runs with one fixed set of options for its whole
+// lifetime. Several independently configured Matchers can coexist in the
+// same process, so two Matchers built with different options but given the
+// same query text (e.g. one extended, one not, both queried with "!abc")
+// would otherwise collide on that key and hand each other's compiled
+// Pattern back. Each Matcher therefore keeps its own private pattern cache,
+// scoped to its own fixed options, the same way the package-level cache is
+// scoped to the CLI's fixed options.
+type Matcher struct {
+	mu       sync.Mutex
+	opts     MatcherOptions
+	chunk    Chunk
+	cache    ChunkCache
+	patterns map[string]*Pattern
+}
+
+// NewMatcher creates a Matcher with no lines fed to it yet.
+func NewMatcher(opts MatcherOptions) *Matcher {
+	return &Matcher{
+		opts:     opts,
+		chunk:    Chunk{},
+		cache:    NewChunkCache(),
+		patterns: make(map[string]*Pattern),
+	}
+}
+
+// Feed appends lines to the Matcher's item store. Previously fed lines are
+// kept, so repeated calls grow the set incrementally; Query always searches
+// everything fed so far.
+func (m *Matcher) Feed(lines []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, line := range lines {
+		text := []rune(line)
+		m.chunk = append(m.chunk, &Item{
+			text:     text,
+			origText: text,
+			index:    int32(len(m.chunk)),
+		})
+	}
+}
+
+// Query runs a single search against every line fed so far and returns the
+// matches in the order the lines were fed; they are not sorted by relevance.
+// A repeat of a cacheable query is looked up in the Matcher's own ChunkCache
+// by its exact CacheKey; unlike Pattern.Match, Query does not narrow the
+// search using cached results for a prefix or suffix of that key.
+func (m *Matcher) Query(q string) []Result {
+	pattern := m.buildPattern(q)
+
+	// Matching fills in each shared Item's tokenized input, so it runs
+	// under m.mu like the rest of the Matcher's state.
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	chunk := m.chunk
+	cache := m.cache
+
+	cacheKey := pattern.CacheKey()
+	var matches []*Item
+	if pattern.cacheable {
+		if cached, found := cache.Find(&chunk, cacheKey); found {
+			matches = cached
+		}
+	}
+	if matches == nil {
+		matches = pattern.matchChunk(&chunk)
+		if pattern.cacheable {
+			cache.Add(&chunk, cacheKey, matches)
+		}
+	}
+
+	results := make([]Result, len(matches))
+	for i, item := range matches {
+		offsets := make([][2]int32, len(item.offsets))
+		for j, offset := range item.offsets {
+			offsets[j] = [2]int32{offset[0], offset[1]}
+		}
+		results[i] = Result{Text: string(item.text), Offsets: offsets}
+	}
+	return results
+}
+
+// buildPattern builds (or reuses, from the Matcher's own private cache) the
+// Pattern for q under this Matcher's fixed options. Unlike BuildPattern, the
+// cache this consults can't be shared with any other Matcher.
+func (m *Matcher) buildPattern(q string) *Pattern {
+	asString := normalizeQuery(m.opts.Extended, []rune(q))
+
+	m.mu.Lock()
+	cached, found := m.patterns[asString]
+	m.mu.Unlock()
+	if found {
+		return cached
+	}
+
+	ptr := newPattern(m.opts.Fuzzy, m.opts.Extended, m.opts.CaseMode, m.opts.Forward,
+		m.opts.Nth, m.opts.Delimiter, asString)
+
+	m.mu.Lock()
+	m.patterns[asString] = ptr
+	m.mu.Unlock()
+	return ptr
+}
+
+// Reset discards every line fed to the Matcher and replaces its ChunkCache
+// and pattern cache with fresh ones, the same way clearChunkCache does for
+// the package-level cache, so the Matcher can be reused for an unrelated set
+// of input.
+func (m *Matcher) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.chunk = Chunk{}
+	m.cache = NewChunkCache()
+	m.patterns = make(map[string]*Pattern)
+}
+
+// Close releases the Matcher's ChunkCache and pattern cache. A Matcher must
+// not be used after Close.
+func (m *Matcher) Close() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.chunk = nil
+	m.cache = nil
+	m.patterns = nil
+}
@@ -0,0 +1,72 @@
+package fzf
+
+import (
+	"sync"
+	"testing"
+)
+
+func testMatcherOptions() MatcherOptions {
+	return MatcherOptions{Fuzzy: true, Extended: true, CaseMode: CaseSmart, Forward: true}
+}
+
+func TestMatcherIncrementalFeedAndQuery(t *testing.T) {
+	m := NewMatcher(testMatcherOptions())
+	m.Feed([]string{"alpha", "beta"})
+	m.Feed([]string{"gamma delta"})
+
+	results := m.Query("gam")
+	if len(results) != 1 || results[0].Text != "gamma delta" {
+		t.Fatalf(`expected a single match for "gamma delta", got %+v`, results)
+	}
+
+	results = m.Query("a")
+	if len(results) != 3 {
+		t.Fatalf(`expected all three fed lines to match "a", got %+v`, results)
+	}
+}
+
+// TestMatcherQueryReusesChunkCache checks that a query populates the
+// Matcher's own ChunkCache under the same key a second, identical query
+// would look up, so repeated queries don't rematch from scratch.
+func TestMatcherQueryReusesChunkCache(t *testing.T) {
+	m := NewMatcher(testMatcherOptions())
+	m.Feed([]string{"alpha", "beta", "gamma"})
+
+	first := m.Query("a")
+
+	m.mu.Lock()
+	chunk := m.chunk
+	cache := m.cache
+	opts := m.opts
+	m.mu.Unlock()
+
+	pattern := BuildPattern(opts.Fuzzy, opts.Extended, opts.CaseMode, opts.Forward,
+		opts.Nth, opts.Delimiter, []rune("a"))
+	cached, found := cache.Find(&chunk, pattern.CacheKey())
+	if !found {
+		t.Fatal("expected Query to have populated the Matcher's ChunkCache")
+	}
+	if len(cached) != len(first) {
+		t.Fatalf("cached result count %d does not match query result count %d", len(cached), len(first))
+	}
+}
+
+// TestMatcherConcurrentQuery exercises Query from many goroutines at once.
+// It exists to catch the kind of "concurrent map writes" crash _patternCache
+// used to be exposed to before it was guarded by _patternMutex; run with
+// -race to also catch data races in ChunkCache or the Matcher's own state.
+func TestMatcherConcurrentQuery(t *testing.T) {
+	m := NewMatcher(testMatcherOptions())
+	m.Feed([]string{"alpha", "beta", "gamma", "delta"})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m.Query("a")
+			m.Query("e")
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,37 @@
+package fzf
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ParseMatchWorkersArg scans args for the --match-workers CLI option, in
+// either "--match-workers=N" or "--match-workers N" form, and applies it via
+// SetMatchWorkers. It returns args with the consumed option (and its value,
+// if given as a separate argument) removed, so the caller's own option
+// parser can keep handling everything else.
+//
+// This file intentionally covers only the flag added alongside
+// SetMatchWorkers; the rest of fzf's long-option parsing (--sort, --tac,
+// --nth, and so on) lives in the CLI's main option parser, which this
+// source tree does not include.
+func ParseMatchWorkersArg(args []string) []string {
+	remaining := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case strings.HasPrefix(arg, "--match-workers="):
+			if n, err := strconv.Atoi(strings.TrimPrefix(arg, "--match-workers=")); err == nil {
+				SetMatchWorkers(n)
+			}
+		case arg == "--match-workers" && i+1 < len(args):
+			if n, err := strconv.Atoi(args[i+1]); err == nil {
+				SetMatchWorkers(n)
+			}
+			i++
+		default:
+			remaining = append(remaining, arg)
+		}
+	}
+	return remaining
+}
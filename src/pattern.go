@@ -2,10 +2,14 @@ package fzf
 
 import (
 	"regexp"
+	"runtime"
 	"sort"
 	"strings"
+	"sync"
+	"unicode/utf8"
 
 	"github.com/junegunn/fzf/src/algo"
+	"github.com/junegunn/fzf/src/migemo"
 	"github.com/junegunn/fzf/src/util"
 )
 
@@ -17,6 +21,9 @@ import (
 // !'not-exact
 // !^not-exact-prefix
 // !not-exact-suffix$
+// ;migemo
+// /regex/
+// /regex/i
 
 type termType int
 
@@ -26,6 +33,8 @@ const (
 	termPrefix
 	termSuffix
 	termEqual
+	termMigemo
+	termRegex
 )
 
 type term struct {
@@ -54,12 +63,18 @@ type Pattern struct {
 
 var (
 	_patternCache map[string]*Pattern
+	_patternMutex sync.Mutex
 	_splitRegex   *regexp.Regexp
+	_termRegex    *regexp.Regexp
+	_regexCache   map[string]*regexp.Regexp
+	_regexMutex   sync.Mutex
 	_cache        ChunkCache
 )
 
 func init() {
 	_splitRegex = regexp.MustCompile("\\s+")
+	_termRegex = regexp.MustCompile(`^/(.*)/([a-zA-Z]*)$`)
+	_regexCache = make(map[string]*regexp.Regexp)
 	clearPatternCache()
 	clearChunkCache()
 }
@@ -67,6 +82,8 @@ func init() {
 func clearPatternCache() {
 	// We can uniquely identify the pattern for a given string since
 	// search mode and caseMode do not change while the program is running
+	_patternMutex.Lock()
+	defer _patternMutex.Unlock()
 	_patternCache = make(map[string]*Pattern)
 }
 
@@ -74,22 +91,48 @@ func clearChunkCache() {
 	_cache = NewChunkCache()
 }
 
-// BuildPattern builds Pattern object from the given arguments
+// normalizeQuery trims an extended-mode query the same way BuildPattern and
+// Matcher.buildPattern both need to, so they agree on a cache key.
+func normalizeQuery(extended bool, runes []rune) string {
+	if extended {
+		return strings.Trim(string(runes), " ")
+	}
+	return string(runes)
+}
+
+// BuildPattern builds Pattern object from the given arguments, consulting
+// and populating the package-level pattern cache. The CLI only ever runs
+// with one fixed fuzzy/extended/caseMode/forward/nth/delimiter combination
+// for its whole lifetime, so keying that cache on the query text alone is
+// safe here. Matcher, which can have several independently configured
+// instances alive in the same process, does not use this cache or this
+// function — see Matcher.buildPattern.
 func BuildPattern(fuzzy bool, extended bool, caseMode Case, forward bool,
 	nth []Range, delimiter Delimiter, runes []rune) *Pattern {
 
-	var asString string
-	if extended {
-		asString = strings.Trim(string(runes), " ")
-	} else {
-		asString = string(runes)
-	}
+	asString := normalizeQuery(extended, runes)
 
+	_patternMutex.Lock()
 	cached, found := _patternCache[asString]
+	_patternMutex.Unlock()
 	if found {
 		return cached
 	}
 
+	ptr := newPattern(fuzzy, extended, caseMode, forward, nth, delimiter, asString)
+
+	_patternMutex.Lock()
+	_patternCache[asString] = ptr
+	_patternMutex.Unlock()
+	return ptr
+}
+
+// newPattern does the actual parsing and construction work for a Pattern.
+// It touches no cache; callers decide how (or whether) to memoize the
+// result.
+func newPattern(fuzzy bool, extended bool, caseMode Case, forward bool,
+	nth []Range, delimiter Delimiter, asString string) *Pattern {
+
 	caseSensitive, cacheable := true, true
 	termSets := []termSet{}
 
@@ -99,8 +142,12 @@ func BuildPattern(fuzzy bool, extended bool, caseMode Case, forward bool,
 		for _, termSet := range termSets {
 			for idx, term := range termSet {
 				// If the query contains inverse search terms or OR operators,
-				// we cannot cache the search scope
-				if idx > 0 || term.inv {
+				// we cannot cache the search scope. The same applies to migemo
+				// terms: a substring of the romaji query does not correspond to
+				// a substring of the regex migemo compiles from it. Regex terms
+				// are excluded for the same reason: a substring of the pattern
+				// source is not generally a valid sub-match of the compiled regex.
+				if idx > 0 || term.inv || term.typ == termMigemo || term.typ == termRegex {
 					cacheable = false
 					break Loop
 				}
@@ -132,8 +179,9 @@ func BuildPattern(fuzzy bool, extended bool, caseMode Case, forward bool,
 	ptr.procFun[termExact] = algo.ExactMatchNaive
 	ptr.procFun[termPrefix] = algo.PrefixMatch
 	ptr.procFun[termSuffix] = algo.SuffixMatch
+	ptr.procFun[termMigemo] = migemoMatch
+	ptr.procFun[termRegex] = regexMatch
 
-	_patternCache[asString] = ptr
 	return ptr
 }
 
@@ -165,7 +213,13 @@ func parseTerms(fuzzy bool, caseMode Case, str string) []termSet {
 			text = text[1:]
 		}
 
-		if strings.HasPrefix(text, "'") {
+		if m := _termRegex.FindStringSubmatch(text); m != nil && regexSource(m[1], m[2]) != "" {
+			typ = termRegex
+			text = regexSource(m[1], m[2])
+		} else if strings.HasPrefix(text, ";") {
+			typ = termMigemo
+			text = text[1:]
+		} else if strings.HasPrefix(text, "'") {
 			// Flip exactness
 			if fuzzy {
 				typ = termExact
@@ -220,21 +274,32 @@ func (p *Pattern) AsString() string {
 	return string(p.text)
 }
 
-// CacheKey is used to build string to be used as the key of result cache
+// CacheKey is used to build string to be used as the key of result cache.
+//
+// Regex terms are excluded here even though they always force
+// p.cacheable = false: Match's prefix/suffix chunk-cache narrowing loop
+// runs on this key unconditionally, regardless of p.cacheable, and a
+// substring of a regex source is not generally a valid sub-match of the
+// compiled regex (the same reasoning that excludes migemo terms below).
+// Queries that differ only in their regex terms therefore share a CacheKey;
+// such patterns are never cacheable, so no result is stored under that key.
 func (p *Pattern) CacheKey() string {
 	if !p.extended {
 		return p.AsString()
 	}
 	cacheableTerms := []string{}
 	for _, termSet := range p.termSets {
-		if len(termSet) == 1 && !termSet[0].inv {
+		if len(termSet) == 1 && !termSet[0].inv && termSet[0].typ != termMigemo && termSet[0].typ != termRegex {
 			cacheableTerms = append(cacheableTerms, string(termSet[0].origText))
 		}
 	}
 	return strings.Join(cacheableTerms, " ")
 }
 
-// Match returns the list of matches Items in the given Chunk
+// Match returns the list of matches Items in the given Chunk. _cache itself
+// is only touched here, before and after matchChunk splits the work across
+// workers, so ChunkCache's own locking (see chunk.go) is unaffected by the
+// parallel matching below.
 func (p *Pattern) Match(chunk *Chunk) []*Item {
 	space := chunk
 
@@ -271,17 +336,80 @@ Loop:
 	return matches
 }
 
+// minItemsPerWorker bounds how finely a chunk is split: splitting a small
+// chunk across goroutines would spend more time scheduling than matching.
+const minItemsPerWorker = 1024
+
+// matchWorkers is the number of goroutines matchChunk splits a chunk across.
+// It defaults to one goroutine per CPU; SetMatchWorkers overrides it. A
+// value <= 1 disables the split entirely.
+//
+// --match-workers is wired up via ParseMatchWorkersArg in options.go, which
+// calls SetMatchWorkers; see that file for the flag syntax.
+var matchWorkers = runtime.NumCPU()
+
+// SetMatchWorkers configures the number of goroutines used to parallelize
+// Pattern.Match over large chunks. A non-positive value falls back to
+// runtime.NumCPU.
+func SetMatchWorkers(workers int) {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	matchWorkers = workers
+}
+
 func (p *Pattern) matchChunk(chunk *Chunk) []*Item {
+	space := []*Item(*chunk)
+	workers := matchWorkers
+	if maxWorkers := len(space) / minItemsPerWorker; workers > maxWorkers {
+		workers = maxWorkers
+	}
+	if workers <= 1 {
+		return p.matchSlice(space)
+	}
+
+	chunkSize := (len(space) + workers - 1) / workers
+	slices := make([][]*Item, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		start := w * chunkSize
+		if start >= len(space) {
+			break
+		}
+		end := start + chunkSize
+		if end > len(space) {
+			end = len(space)
+		}
+		wg.Add(1)
+		go func(w, start, end int) {
+			defer wg.Done()
+			slices[w] = p.matchSlice(space[start:end])
+		}(w, start, end)
+	}
+	wg.Wait()
+
+	matches := []*Item{}
+	for _, sub := range slices {
+		matches = append(matches, sub...)
+	}
+	return matches
+}
+
+// matchSlice runs the matcher over a slice of a chunk. It is called
+// concurrently by matchChunk, one call per worker, each over a disjoint
+// slice, so it must not touch any shared mutable state; dupItem only
+// allocates a new Item from its arguments, so it is safe to call this way.
+func (p *Pattern) matchSlice(space []*Item) []*Item {
 	matches := []*Item{}
 	if !p.extended {
-		for _, item := range *chunk {
+		for _, item := range space {
 			if sidx, eidx, tlen := p.basicMatch(item); sidx >= 0 {
 				matches = append(matches,
 					dupItem(item, []Offset{Offset{int32(sidx), int32(eidx), int32(tlen)}}))
 			}
 		}
 	} else {
-		for _, item := range *chunk {
+		for _, item := range space {
 			if offsets := p.extendedMatch(item); len(offsets) == len(p.termSets) {
 				matches = append(matches, dupItem(item, offsets))
 			}
@@ -358,6 +486,60 @@ func (p *Pattern) prepareInput(item *Item) []Token {
 	return ret
 }
 
+// regexSource builds the regex source used as a regex term's text, baking
+// in case-insensitivity from a trailing "i" flag. It returns "" if the
+// resulting expression does not compile, in which case the token is left
+// for the normal fuzzy/exact sigils to handle.
+func regexSource(pattern string, flags string) string {
+	source := pattern
+	if strings.Contains(flags, "i") {
+		source = "(?i)" + source
+	}
+	if _, err := regexp.Compile(source); err != nil {
+		return ""
+	}
+	return source
+}
+
+// compileRegexCached compiles a regex term exactly once per distinct source
+// and reuses it across items and queries.
+func compileRegexCached(source string) *regexp.Regexp {
+	_regexMutex.Lock()
+	defer _regexMutex.Unlock()
+
+	if re, found := _regexCache[source]; found {
+		return re
+	}
+	re := regexp.MustCompile(source)
+	_regexCache[source] = re
+	return re
+}
+
+// regexMatch runs a compiled regex term against the input and converts the
+// byte offsets FindStringIndex returns into rune offsets, matching the rest
+// of the procFun family.
+func regexMatch(caseSensitive bool, forward bool, input []rune, pattern []rune) (int, int) {
+	re := compileRegexCached(string(pattern))
+	s := string(input)
+	loc := re.FindStringIndex(s)
+	if loc == nil {
+		return -1, -1
+	}
+	return utf8.RuneCountInString(s[:loc[0]]), utf8.RuneCountInString(s[:loc[1]])
+}
+
+// migemoMatch adapts migemo.FindStringIndex, which compiles pattern into a
+// regex covering hiragana/katakana/kanji readings of the romaji input, to
+// the procFun signature shared by the other term types. FindStringIndex
+// returns a rune start offset and a rune length, so the end is their sum.
+func migemoMatch(caseSensitive bool, forward bool, input []rune, pattern []rune) (int, int) {
+	indexes := migemo.FindStringIndex(string(input), string(pattern))
+	if indexes == nil {
+		return -1, -1
+	}
+	return indexes[0], indexes[0] + indexes[1]
+}
+
 func (p *Pattern) iter(pfun func(bool, bool, []rune, []rune) (int, int),
 	tokens []Token, caseSensitive bool, forward bool, pattern []rune) (int, int, int) {
 	for _, part := range tokens {
@@ -0,0 +1,96 @@
+package fzf
+
+import "testing"
+
+func TestParseTermsMigemoSigil(t *testing.T) {
+	sets := parseTerms(true, CaseSmart, ";kensaku")
+	if len(sets) != 1 || len(sets[0]) != 1 {
+		t.Fatalf("expected a single migemo term, got %+v", sets)
+	}
+	term := sets[0][0]
+	if term.typ != termMigemo {
+		t.Fatalf("expected termMigemo, got %v", term.typ)
+	}
+	if string(term.text) != "kensaku" {
+		t.Fatalf("expected the sigil stripped from term text, got %q", string(term.text))
+	}
+}
+
+func TestParseTermsMigemoEmptyAfterSigil(t *testing.T) {
+	// A bare ";" has nothing left once the sigil is stripped, so - like a
+	// bare "'" or "^" - it should not produce a term at all.
+	sets := parseTerms(true, CaseSmart, ";")
+	if len(sets) != 0 {
+		t.Fatalf("expected no term sets for a bare sigil, got %+v", sets)
+	}
+}
+
+func TestBuildPatternMigemoIsNotCacheable(t *testing.T) {
+	clearPatternCache()
+	p := BuildPattern(true, true, CaseSmart, true, nil, Delimiter{}, []rune(";kensaku"))
+	if p.cacheable {
+		t.Fatal("expected a migemo term to force cacheable=false")
+	}
+	if p.CacheKey() != "" {
+		t.Fatalf("expected CacheKey to exclude the migemo term, got %q", p.CacheKey())
+	}
+}
+
+func TestParseTermsRegexWithFlag(t *testing.T) {
+	sets := parseTerms(true, CaseSmart, "/^foo.*bar$/i")
+	if len(sets) != 1 || len(sets[0]) != 1 {
+		t.Fatalf("expected a single regex term, got %+v", sets)
+	}
+	term := sets[0][0]
+	if term.typ != termRegex {
+		t.Fatalf("expected termRegex, got %v", term.typ)
+	}
+	if string(term.text) != "(?i)^foo.*bar$" {
+		t.Fatalf("expected the i flag to be baked in as (?i), got %q", string(term.text))
+	}
+}
+
+func TestParseTermsRegexFlagIndependentOfCaseMode(t *testing.T) {
+	// The trailing "i" flag controls the compiled regex directly; it is
+	// not overridden by (or merged with) the query's caseMode.
+	sets := parseTerms(true, CaseRespect, "/Foo/i")
+	term := sets[0][0]
+	if term.typ != termRegex {
+		t.Fatalf("expected termRegex, got %v", term.typ)
+	}
+	if string(term.text) != "(?i)Foo" {
+		t.Fatalf("expected (?i) to be baked into the source regardless of CaseRespect, got %q", string(term.text))
+	}
+}
+
+func TestParseTermsInvalidRegexFallsThroughToFuzzy(t *testing.T) {
+	// "/[/" doesn't compile (unterminated character class), so the token
+	// should fall through and be treated as an ordinary fuzzy term rather
+	// than erroring out.
+	sets := parseTerms(true, CaseSmart, "/[/")
+	if len(sets) != 1 || len(sets[0]) != 1 {
+		t.Fatalf("expected a single fallback term, got %+v", sets)
+	}
+	term := sets[0][0]
+	if term.typ != termFuzzy {
+		t.Fatalf("expected an invalid regex to fall back to termFuzzy, got %v", term.typ)
+	}
+	if string(term.text) != "/[/" {
+		t.Fatalf("expected the raw token to be kept as-is, got %q", string(term.text))
+	}
+}
+
+func TestBuildPatternRegexIsNotCacheableAndExcludedFromCacheKey(t *testing.T) {
+	clearPatternCache()
+	p := BuildPattern(true, true, CaseSmart, true, nil, Delimiter{}, []rune("/foo/"))
+	if p.cacheable {
+		t.Fatal("expected a regex term to force cacheable=false")
+	}
+	// CacheKey deliberately excludes regex terms too: Match's prefix/suffix
+	// chunk-cache narrowing runs on CacheKey unconditionally, and a
+	// substring of a regex source is not generally a valid sub-match of
+	// the compiled regex (see the comment on this exclusion in CacheKey).
+	if p.CacheKey() != "" {
+		t.Fatalf("expected CacheKey to exclude the regex term, got %q", p.CacheKey())
+	}
+}